@@ -0,0 +1,40 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/dlukt/namedconf"
+)
+
+// ACLStmt is the typed view of a top-level `acl "name" { ... };` block. The
+// body is a flat address-match list (IPs, CIDRs, other ACL names, or
+// "!"-negated entries), so unlike most other block statements here it has
+// no Extra -- every entry is an Entries value.
+type ACLStmt struct {
+	Stmt *namedconf.Stmt
+
+	Name    string
+	Entries []string
+}
+
+func (a *ACLStmt) CST() *namedconf.Stmt { return a.Stmt }
+
+func decodeACL(s *namedconf.Stmt) *ACLStmt {
+	a := &ACLStmt{Stmt: s}
+	if f := fields(s.HeadRaw); len(f) > 1 {
+		a.Name = f[1]
+	}
+	a.Entries = listValues(s)
+	return a
+}
+
+func (a *ACLStmt) sync() {
+	a.Stmt.HeadRaw = "acl " + strconv.Quote(a.Name)
+	body := make([]namedconf.Node, 0, len(a.Entries))
+	for _, e := range a.Entries {
+		body = append(body, namedconf.NewSimpleStmt(e))
+	}
+	a.Stmt.Body = body
+	a.Stmt.HasBlock = true
+	a.Stmt.Modified = true
+}
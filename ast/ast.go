@@ -0,0 +1,116 @@
+// Package ast provides a typed overlay over namedconf's concrete-syntax
+// Stmt for the BIND statements tools usually want to inspect or edit
+// directly -- options, zone, acl, view, key, logging, server, controls, and
+// include -- so callers don't have to hand-parse HeadRaw/Body themselves.
+//
+// The CST (namedconf.Stmt) remains the source of truth; a typed Node is a
+// decoded view of one Stmt plus a handle back to it. Edit the Node's fields
+// and call To to regenerate just the CST it owns.
+package ast
+
+import (
+	"fmt"
+
+	"github.com/dlukt/namedconf"
+)
+
+// Node is a typed overlay over a namedconf.Stmt.
+type Node interface {
+	// CST returns the namedconf.Stmt this node was decoded from (or will
+	// regenerate into, once passed through To).
+	CST() *namedconf.Stmt
+}
+
+// syncer is implemented by every concrete Node; sync writes the node's
+// current field values back into its CST Stmt.
+type syncer interface {
+	sync()
+}
+
+// UnsupportedError is returned by From for a keyword with no typed overlay.
+type UnsupportedError struct {
+	Keyword string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("ast: no typed overlay for keyword %q", e.Keyword)
+}
+
+// From pattern-matches s.Keyword and decodes s into its typed Node. It
+// returns an *UnsupportedError for keywords with no typed overlay; callers
+// walking a whole file can simply skip those.
+func From(s *namedconf.Stmt) (Node, error) {
+	switch s.Keyword {
+	case "options":
+		return decodeOptions(s), nil
+	case "zone":
+		return decodeZone(s), nil
+	case "acl":
+		return decodeACL(s), nil
+	case "view":
+		return decodeView(s), nil
+	case "key":
+		return decodeKey(s), nil
+	case "logging":
+		return decodeLogging(s), nil
+	case "server":
+		return decodeServer(s), nil
+	case "controls":
+		return decodeControls(s), nil
+	case "include":
+		return decodeInclude(s), nil
+	default:
+		return nil, &UnsupportedError{Keyword: s.Keyword}
+	}
+}
+
+// To regenerates n's underlying CST statement from its current field
+// values -- marking it (and any CST children it owns) Modified -- and
+// returns it, ready to AppendToBody or splice into a File's Nodes.
+//
+// Unchanged siblings are untouched: To only invalidates the Stmt(s) this
+// node actually owns, never the surrounding Raw trivia or other statements.
+func To(n Node) *namedconf.Stmt {
+	if s, ok := n.(syncer); ok {
+		s.sync()
+	}
+	return n.CST()
+}
+
+// Typed decodes every top-level statement of f that From recognizes,
+// skipping the rest.
+//
+// This is a free function rather than a File.Typed method: namedconf.File
+// can't return an ast.Node without namedconf importing ast, which would
+// cycle back against ast's own import of namedconf. Call ast.Typed(f)
+// wherever a File.Typed() method might otherwise be expected.
+func Typed(f *namedconf.File) []Node {
+	var out []Node
+	for _, n := range f.Nodes {
+		if s, ok := n.(*namedconf.Stmt); ok {
+			if node, err := From(s); err == nil {
+				out = append(out, node)
+			}
+		}
+	}
+	return out
+}
+
+// Walk calls fn for every typed node reachable from f, recursing into
+// blocks so that e.g. a zone nested inside a view is visited too.
+func Walk(f *namedconf.File, fn func(Node)) {
+	var walk func(nodes []namedconf.Node)
+	walk = func(nodes []namedconf.Node) {
+		for _, n := range nodes {
+			s, ok := n.(*namedconf.Stmt)
+			if !ok {
+				continue
+			}
+			if node, err := From(s); err == nil {
+				fn(node)
+			}
+			walk(s.Body)
+		}
+	}
+	walk(f.Nodes)
+}
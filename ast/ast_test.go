@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dlukt/namedconf"
+)
+
+func TestDecodeZone(t *testing.T) {
+	src := []byte(`zone "example.com" IN {
+  type master;
+  file "db.example.com";
+  allow-transfer { 10.0.0.1; 10.0.0.2; };
+};
+`)
+	f, err := namedconf.Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	stmts := f.TopLevel("zone")
+	if len(stmts) != 1 {
+		t.Fatalf("expected 1 zone statement, got %d", len(stmts))
+	}
+	node, err := From(stmts[0])
+	if err != nil {
+		t.Fatalf("From: %v", err)
+	}
+	z, ok := node.(*ZoneStmt)
+	if !ok {
+		t.Fatalf("expected *ZoneStmt, got %T", node)
+	}
+	if z.Name != "example.com" || z.Class != "IN" || z.Type != "master" || z.File != "db.example.com" {
+		t.Fatalf("unexpected decode: %+v", z)
+	}
+	if strings.Join(z.AllowTransfer, ",") != "10.0.0.1,10.0.0.2" {
+		t.Fatalf("unexpected AllowTransfer: %v", z.AllowTransfer)
+	}
+}
+
+func TestZoneSyncPreservesUnrelatedSiblings(t *testing.T) {
+	src := []byte(`options { recursion no; };
+zone "example.com" IN {
+  type master;
+  file "db.example.com";
+};
+`)
+	f, err := namedconf.Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	zoneStmt := f.TopLevel("zone")[0]
+	node, _ := From(zoneStmt)
+	z := node.(*ZoneStmt)
+	z.File = "db.example.com.new"
+	To(z)
+
+	out := string(f.Bytes())
+	if !strings.Contains(out, `recursion no;`) {
+		t.Fatalf("expected unmodified options statement to survive verbatim, got:\n%s", out)
+	}
+	if !strings.Contains(out, `file "db.example.com.new"`) {
+		t.Fatalf("expected updated file value, got:\n%s", out)
+	}
+}
@@ -0,0 +1,21 @@
+package ast
+
+import "github.com/dlukt/namedconf"
+
+// ControlsStmt is the typed view of the top-level `controls { ... };`
+// block used to configure rndc's listener(s). Its inet/unix entries are
+// exposed as Extra for now rather than individually-named fields.
+type ControlsStmt struct {
+	Stmt  *namedconf.Stmt
+	Extra []*namedconf.Stmt
+}
+
+func (c *ControlsStmt) CST() *namedconf.Stmt { return c.Stmt }
+
+func decodeControls(s *namedconf.Stmt) *ControlsStmt {
+	return &ControlsStmt{Stmt: s, Extra: bodyStmts(s.Body)}
+}
+
+func (c *ControlsStmt) sync() {
+	c.Stmt.Modified = true
+}
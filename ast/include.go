@@ -0,0 +1,24 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/dlukt/namedconf"
+)
+
+// IncludeStmt is the typed view of a top-level `include "path";` directive.
+type IncludeStmt struct {
+	Stmt     *namedconf.Stmt
+	Filename string
+}
+
+func (i *IncludeStmt) CST() *namedconf.Stmt { return i.Stmt }
+
+func decodeInclude(s *namedconf.Stmt) *IncludeStmt {
+	return &IncludeStmt{Stmt: s, Filename: firstValue(s)}
+}
+
+func (i *IncludeStmt) sync() {
+	i.Stmt.HeadRaw = "include " + strconv.Quote(i.Filename)
+	i.Stmt.Modified = true
+}
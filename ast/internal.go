@@ -0,0 +1,126 @@
+package ast
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dlukt/namedconf"
+)
+
+// fields splits s on whitespace, treating a double-quoted span as one field
+// (quotes stripped) so names like "example.com" survive intact.
+func fields(s string) []string {
+	var out []string
+	var cur strings.Builder
+	inQuote := false
+	flush := func() {
+		if cur.Len() > 0 {
+			out = append(out, cur.String())
+			cur.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case !inQuote && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			flush()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	flush()
+	return out
+}
+
+// bodyStmts returns the *namedconf.Stmt children of body, skipping Raw trivia.
+func bodyStmts(body []namedconf.Node) []*namedconf.Stmt {
+	var out []*namedconf.Stmt
+	for _, n := range body {
+		if s, ok := n.(*namedconf.Stmt); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// firstValue returns the text of s.HeadRaw after its leading keyword, e.g.
+// "db.example.com" for a `file "db.example.com"` statement.
+func firstValue(s *namedconf.Stmt) string {
+	f := fields(s.HeadRaw)
+	if len(f) > 1 {
+		return strings.Join(f[1:], " ")
+	}
+	return ""
+}
+
+// listValues decodes a block statement's body as a flat list of bare words,
+// e.g. ["10.0.0.1", "10.0.0.2"] for `masters { 10.0.0.1; 10.0.0.2; };`.
+func listValues(s *namedconf.Stmt) []string {
+	var out []string
+	for _, child := range bodyStmts(s.Body) {
+		if v := strings.Join(fields(child.HeadRaw), " "); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// findChild returns the first body child with the given keyword.
+func findChild(s *namedconf.Stmt, keyword string) (*namedconf.Stmt, int) {
+	for i, n := range s.Body {
+		if st, ok := n.(*namedconf.Stmt); ok && st.Keyword == keyword {
+			return st, i
+		}
+	}
+	return nil, -1
+}
+
+// setOrReplaceChild sets parent's "<keyword> <value>;" child statement,
+// replacing it in place if present or appending it otherwise. An empty
+// value removes the child (the field was cleared).
+func setOrReplaceChild(parent *namedconf.Stmt, keyword, value string, quote bool) {
+	child, idx := findChild(parent, keyword)
+	if value == "" {
+		if idx >= 0 {
+			parent.Body = append(parent.Body[:idx], parent.Body[idx+1:]...)
+		}
+		return
+	}
+	v := value
+	if quote {
+		v = strconv.Quote(value)
+	}
+	head := keyword + " " + v
+	if child != nil {
+		child.HeadRaw = head
+		child.Modified = true
+		return
+	}
+	parent.AppendToBody(namedconf.NewSimpleStmt(head))
+}
+
+// setListChild sets parent's "<keyword> { v1; v2; ... };" child block,
+// replacing it in place if present or appending it otherwise. An empty
+// slice removes the child.
+func setListChild(parent *namedconf.Stmt, keyword string, values []string) {
+	child, idx := findChild(parent, keyword)
+	if len(values) == 0 {
+		if idx >= 0 {
+			parent.Body = append(parent.Body[:idx], parent.Body[idx+1:]...)
+		}
+		return
+	}
+	body := make([]namedconf.Node, 0, len(values))
+	for _, v := range values {
+		body = append(body, namedconf.NewSimpleStmt(v))
+	}
+	if child != nil {
+		child.Body = body
+		child.HasBlock = true
+		child.Modified = true
+		return
+	}
+	parent.AppendToBody(namedconf.NewBlockStmt(keyword, body))
+}
@@ -0,0 +1,42 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/dlukt/namedconf"
+)
+
+// KeyStmt is the typed view of a top-level `key "name" { ... };` block used
+// for TSIG keys (rndc, transfer, notify authentication, ...).
+type KeyStmt struct {
+	Stmt *namedconf.Stmt
+
+	Name      string
+	Algorithm string
+	Secret    string
+}
+
+func (k *KeyStmt) CST() *namedconf.Stmt { return k.Stmt }
+
+func decodeKey(s *namedconf.Stmt) *KeyStmt {
+	k := &KeyStmt{Stmt: s}
+	if f := fields(s.HeadRaw); len(f) > 1 {
+		k.Name = f[1]
+	}
+	for _, child := range bodyStmts(s.Body) {
+		switch child.Keyword {
+		case "algorithm":
+			k.Algorithm = firstValue(child)
+		case "secret":
+			k.Secret = firstValue(child)
+		}
+	}
+	return k
+}
+
+func (k *KeyStmt) sync() {
+	k.Stmt.HeadRaw = "key " + strconv.Quote(k.Name)
+	setOrReplaceChild(k.Stmt, "algorithm", k.Algorithm, false)
+	setOrReplaceChild(k.Stmt, "secret", k.Secret, true)
+	k.Stmt.Modified = true
+}
@@ -0,0 +1,21 @@
+package ast
+
+import "github.com/dlukt/namedconf"
+
+// LoggingStmt is the typed view of the top-level `logging { ... };` block.
+// Its channel/category entries vary too widely to be worth promoting to
+// named fields individually, so they are exposed as Extra for now.
+type LoggingStmt struct {
+	Stmt  *namedconf.Stmt
+	Extra []*namedconf.Stmt
+}
+
+func (l *LoggingStmt) CST() *namedconf.Stmt { return l.Stmt }
+
+func decodeLogging(s *namedconf.Stmt) *LoggingStmt {
+	return &LoggingStmt{Stmt: s, Extra: bodyStmts(s.Body)}
+}
+
+func (l *LoggingStmt) sync() {
+	l.Stmt.Modified = true
+}
@@ -0,0 +1,44 @@
+package ast
+
+import "github.com/dlukt/namedconf"
+
+// OptionsStmt is the typed view of a top-level `options { ... };` block.
+// Fields not yet promoted to their own field live in Extra untouched.
+type OptionsStmt struct {
+	Stmt *namedconf.Stmt
+
+	Directory string
+	Recursion *bool // nil if the statement doesn't set recursion
+
+	Extra []*namedconf.Stmt
+}
+
+func (o *OptionsStmt) CST() *namedconf.Stmt { return o.Stmt }
+
+func decodeOptions(s *namedconf.Stmt) *OptionsStmt {
+	o := &OptionsStmt{Stmt: s}
+	for _, child := range bodyStmts(s.Body) {
+		switch child.Keyword {
+		case "directory":
+			o.Directory = firstValue(child)
+		case "recursion":
+			yes := firstValue(child) == "yes"
+			o.Recursion = &yes
+		default:
+			o.Extra = append(o.Extra, child)
+		}
+	}
+	return o
+}
+
+func (o *OptionsStmt) sync() {
+	setOrReplaceChild(o.Stmt, "directory", o.Directory, true)
+	if o.Recursion != nil {
+		v := "no"
+		if *o.Recursion {
+			v = "yes"
+		}
+		setOrReplaceChild(o.Stmt, "recursion", v, false)
+	}
+	o.Stmt.Modified = true
+}
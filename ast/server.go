@@ -0,0 +1,38 @@
+package ast
+
+import "github.com/dlukt/namedconf"
+
+// ServerStmt is the typed view of a top-level `server <address> { ... };`
+// block, which configures per-remote-server behavior (keys, transfer
+// format, ...).
+type ServerStmt struct {
+	Stmt *namedconf.Stmt
+
+	Address string
+	Keys    []string
+
+	Extra []*namedconf.Stmt
+}
+
+func (sv *ServerStmt) CST() *namedconf.Stmt { return sv.Stmt }
+
+func decodeServer(s *namedconf.Stmt) *ServerStmt {
+	sv := &ServerStmt{Stmt: s}
+	if f := fields(s.HeadRaw); len(f) > 1 {
+		sv.Address = f[1]
+	}
+	for _, child := range bodyStmts(s.Body) {
+		if child.Keyword == "keys" {
+			sv.Keys = listValues(child)
+			continue
+		}
+		sv.Extra = append(sv.Extra, child)
+	}
+	return sv
+}
+
+func (sv *ServerStmt) sync() {
+	sv.Stmt.HeadRaw = "server " + sv.Address
+	setListChild(sv.Stmt, "keys", sv.Keys)
+	sv.Stmt.Modified = true
+}
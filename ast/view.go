@@ -0,0 +1,51 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/dlukt/namedconf"
+)
+
+// ViewStmt is the typed view of a top-level `view "name" { ... };` block.
+// Nested zones are decoded too; syncing a ViewStmt syncs them in turn.
+type ViewStmt struct {
+	Stmt *namedconf.Stmt
+
+	Name         string
+	MatchClients []string
+	Zones        []*ZoneStmt
+
+	Extra []*namedconf.Stmt
+}
+
+func (v *ViewStmt) CST() *namedconf.Stmt { return v.Stmt }
+
+func decodeView(s *namedconf.Stmt) *ViewStmt {
+	v := &ViewStmt{Stmt: s}
+	if f := fields(s.HeadRaw); len(f) > 1 {
+		v.Name = f[1]
+	}
+	for _, child := range bodyStmts(s.Body) {
+		switch child.Keyword {
+		case "match-clients":
+			v.MatchClients = listValues(child)
+		case "zone":
+			v.Zones = append(v.Zones, decodeZone(child))
+		default:
+			v.Extra = append(v.Extra, child)
+		}
+	}
+	return v
+}
+
+func (v *ViewStmt) sync() {
+	v.Stmt.HeadRaw = "view " + strconv.Quote(v.Name)
+	setListChild(v.Stmt, "match-clients", v.MatchClients)
+	// Zones were decoded from (and still point at) children already present
+	// in v.Stmt.Body, so syncing them in place is enough -- this does not
+	// pick up zones appended to the Zones slice after decoding.
+	for _, z := range v.Zones {
+		z.sync()
+	}
+	v.Stmt.Modified = true
+}
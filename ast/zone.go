@@ -0,0 +1,68 @@
+package ast
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dlukt/namedconf"
+)
+
+// ZoneStmt is the typed view of a top-level or view-nested
+// `zone "name" CLASS { ... };` block.
+type ZoneStmt struct {
+	Stmt *namedconf.Stmt
+
+	Name  string
+	Class string // "" if omitted; otherwise e.g. "IN", "CH"
+
+	Type          string
+	File          string
+	Masters       []string
+	AllowTransfer []string
+	AlsoNotify    []string
+
+	Extra []*namedconf.Stmt
+}
+
+func (z *ZoneStmt) CST() *namedconf.Stmt { return z.Stmt }
+
+func decodeZone(s *namedconf.Stmt) *ZoneStmt {
+	z := &ZoneStmt{Stmt: s}
+	if f := fields(s.HeadRaw); len(f) > 1 {
+		z.Name = f[1]
+		if len(f) > 2 {
+			z.Class = strings.ToUpper(f[2])
+		}
+	}
+	for _, child := range bodyStmts(s.Body) {
+		switch child.Keyword {
+		case "type":
+			z.Type = firstValue(child)
+		case "file":
+			z.File = firstValue(child)
+		case "masters":
+			z.Masters = listValues(child)
+		case "allow-transfer":
+			z.AllowTransfer = listValues(child)
+		case "also-notify":
+			z.AlsoNotify = listValues(child)
+		default:
+			z.Extra = append(z.Extra, child)
+		}
+	}
+	return z
+}
+
+func (z *ZoneStmt) sync() {
+	head := "zone " + strconv.Quote(z.Name)
+	if z.Class != "" {
+		head += " " + z.Class
+	}
+	z.Stmt.HeadRaw = head
+	setOrReplaceChild(z.Stmt, "type", z.Type, false)
+	setOrReplaceChild(z.Stmt, "file", z.File, true)
+	setListChild(z.Stmt, "masters", z.Masters)
+	setListChild(z.Stmt, "allow-transfer", z.AllowTransfer)
+	setListChild(z.Stmt, "also-notify", z.AlsoNotify)
+	z.Stmt.Modified = true
+}
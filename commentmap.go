@@ -0,0 +1,289 @@
+package namedconf
+
+import "strings"
+
+// Association classifies how a Comment relates to the Stmt CommentMap
+// attributes it to.
+type Association int
+
+const (
+	// Free marks a comment CommentMap couldn't attribute to any statement
+	// (stored under the nil key).
+	Free Association = iota
+	// Doc marks a comment (or run of comments) immediately preceding a
+	// statement, separated by at most one blank line -- the same heuristic
+	// go/ast.CommentMap uses for doc comments.
+	Doc
+	// Line marks a comment trailing a statement on the same source line.
+	Line
+)
+
+// CommentAssoc is one Comment together with how CommentMap associated it.
+type CommentAssoc struct {
+	Comment *Comment
+	Kind    Association
+}
+
+// CommentMap associates the Comment trivia in a File with the statements
+// they document. Build one with File.CommentMap.
+type CommentMap map[*Stmt][]*CommentAssoc
+
+// Doc returns s's leading (Doc-associated) comments, in source order.
+func (cm CommentMap) Doc(s *Stmt) []*Comment {
+	var out []*Comment
+	for _, a := range cm[s] {
+		if a.Kind == Doc {
+			out = append(out, a.Comment)
+		}
+	}
+	return out
+}
+
+// Line returns s's trailing (Line-associated) comment, or nil.
+func (cm CommentMap) Line(s *Stmt) *Comment {
+	for _, a := range cm[s] {
+		if a.Kind == Line {
+			return a.Comment
+		}
+	}
+	return nil
+}
+
+// Filter returns the subset of cm whose statements appear (recursively)
+// within nodes.
+func (cm CommentMap) Filter(nodes []Node) CommentMap {
+	keep := map[*Stmt]bool{}
+	var collect func(ns []Node)
+	collect = func(ns []Node) {
+		for _, n := range ns {
+			if s, ok := n.(*Stmt); ok {
+				keep[s] = true
+				collect(s.Body)
+			}
+		}
+	}
+	collect(nodes)
+	out := CommentMap{}
+	for s, v := range cm {
+		if s != nil && keep[s] {
+			out[s] = v
+		}
+	}
+	return out
+}
+
+// CommentMap walks f.Nodes (recursively, through every Stmt's Body) and
+// associates each Comment with the statement it documents or trails.
+// Comments attributed to neither are returned under the nil key with
+// Association Free.
+func (f *File) CommentMap() CommentMap {
+	cm := CommentMap{}
+	assigned := map[*Comment]bool{}
+	var allComments []*Comment
+
+	var walk func(nodes []Node)
+	walk = func(nodes []Node) {
+		for _, n := range nodes {
+			switch v := n.(type) {
+			case *Comment:
+				allComments = append(allComments, v)
+			case *Stmt:
+				for _, c := range v.docComments {
+					cm[v] = append(cm[v], &CommentAssoc{Comment: c, Kind: Doc})
+					assigned[c] = true
+				}
+				if v.lineComment != nil {
+					cm[v] = append(cm[v], &CommentAssoc{Comment: v.lineComment, Kind: Line})
+					assigned[v.lineComment] = true
+				}
+				walk(v.Body)
+			}
+		}
+	}
+	walk(f.Nodes)
+
+	for _, c := range allComments {
+		if !assigned[c] {
+			cm[nil] = append(cm[nil], &CommentAssoc{Comment: c, Kind: Free})
+		}
+	}
+	return cm
+}
+
+// Doc returns the concatenated text of s's leading doc comments (delimiters
+// and surrounding space stripped, one per line), or "" if it has none.
+func (s *Stmt) Doc() string {
+	if len(s.docComments) == 0 {
+		return ""
+	}
+	parts := make([]string, len(s.docComments))
+	for i, c := range s.docComments {
+		parts[i] = stripCommentDelims(c.Text)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// linkComments computes each Stmt's docComments and lineComment from its
+// position among siblings. Called once per parsed node list, right after
+// it's built, so File.CommentMap and Stmt.Doc never need to recompute it.
+//
+// Line comments are resolved first, and a comment claimed as one Stmt's
+// lineComment is never also eligible as the *next* Stmt's doc comment --
+// without that exclusion, a same-line trailing comment immediately
+// followed (no blank line) by another statement would be attributed to
+// both, the way go/ast.CommentMap instead treats each comment as
+// belonging to exactly one node.
+func linkComments(nodes []Node) {
+	claimed := map[*Comment]bool{}
+	for i, n := range nodes {
+		s, ok := n.(*Stmt)
+		if !ok {
+			continue
+		}
+		s.lineComment = lineCommentAt(nodes, i)
+		if s.lineComment != nil {
+			claimed[s.lineComment] = true
+		}
+	}
+	for i, n := range nodes {
+		s, ok := n.(*Stmt)
+		if !ok {
+			continue
+		}
+		_, s.docComments = docSpan(nodes, i, claimed)
+	}
+}
+
+// docSpan returns the run of comments preceding nodes[stmtIdx], along with
+// the index of the earliest node in that span. The run may be separated
+// from the statement by at most one blank line; the comments within it are
+// separated from each other by single newlines, so a blank line between two
+// comments ends the scan, and so does reaching a comment already in claimed
+// (some preceding Stmt's lineComment).
+func docSpan(nodes []Node, stmtIdx int, claimed map[*Comment]bool) (start int, comments []*Comment) {
+	start = stmtIdx
+	idx := stmtIdx - 1
+	if idx < 0 {
+		return start, nil
+	}
+	if ws, ok := nodes[idx].(*Whitespace); ok && countNewlines(ws.Text) <= 2 {
+		idx--
+	} else {
+		return start, nil
+	}
+	for idx >= 0 {
+		c, ok := nodes[idx].(*Comment)
+		if !ok || claimed[c] {
+			break
+		}
+		comments = append([]*Comment{c}, comments...)
+		start = idx
+		idx--
+		if idx < 0 {
+			break
+		}
+		if ws, ok := nodes[idx].(*Whitespace); ok && countNewlines(ws.Text) <= 1 {
+			idx--
+			continue
+		}
+		break
+	}
+	return start, comments
+}
+
+// lineCommentAt returns a Comment trailing nodes[stmtIdx] on the same
+// source line, or nil.
+func lineCommentAt(nodes []Node, stmtIdx int) *Comment {
+	idx := stmtIdx + 1
+	if idx >= len(nodes) {
+		return nil
+	}
+	if ws, ok := nodes[idx].(*Whitespace); ok {
+		if countNewlines(ws.Text) > 0 {
+			return nil
+		}
+		idx++
+	}
+	if idx < len(nodes) {
+		if c, ok := nodes[idx].(*Comment); ok {
+			return c
+		}
+	}
+	return nil
+}
+
+func countNewlines(s string) int {
+	n := 0
+	for _, r := range s {
+		if r == '\n' {
+			n++
+		}
+	}
+	return n
+}
+
+// stripCommentDelims trims a comment's delimiters and surrounding space,
+// e.g. "# foo " -> "foo", "/* foo */" -> "foo".
+func stripCommentDelims(text string) string {
+	switch {
+	case strings.HasPrefix(text, "//"):
+		return strings.TrimSpace(strings.TrimPrefix(text, "//"))
+	case strings.HasPrefix(text, "#"):
+		return strings.TrimSpace(strings.TrimPrefix(text, "#"))
+	case strings.HasPrefix(text, "/*"):
+		return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/"))
+	default:
+		return text
+	}
+}
+
+// Delete removes s from the tree -- searching f.Nodes and every nested
+// Body -- along with the Doc comments CommentMap would have attributed to
+// it. It reports whether s was found.
+func (f *File) Delete(s *Stmt) bool {
+	return deleteFrom(&f.Nodes, s)
+}
+
+func deleteFrom(nodes *[]Node, target *Stmt) bool {
+	for i, n := range *nodes {
+		st, ok := n.(*Stmt)
+		if !ok {
+			continue
+		}
+		if st == target {
+			start := docSpanStart(*nodes, i, len(st.docComments))
+			*nodes = append(append([]Node{}, (*nodes)[:start]...), (*nodes)[i+1:]...)
+			return true
+		}
+		if deleteFrom(&st.Body, target) {
+			st.Modified = true
+			return true
+		}
+	}
+	return false
+}
+
+// docSpanStart returns the index of the earliest node belonging to the
+// nDocs doc comments already attributed to nodes[stmtIdx] (by linkComments),
+// walking back exactly that many Comment nodes across their interleaving
+// Whitespace. Using the cached count, rather than re-deriving the span from
+// scratch, keeps Delete from re-claiming a comment linkComments already
+// gave to a preceding Stmt's lineComment.
+func docSpanStart(nodes []Node, stmtIdx, nDocs int) int {
+	start := stmtIdx
+	idx := stmtIdx - 1
+	for seen := 0; seen < nDocs && idx >= 0; seen++ {
+		if _, ok := nodes[idx].(*Whitespace); ok {
+			idx--
+		}
+		if idx < 0 {
+			break
+		}
+		if _, ok := nodes[idx].(*Comment); !ok {
+			break
+		}
+		start = idx
+		idx--
+	}
+	return start
+}
@@ -0,0 +1,105 @@
+package namedconf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCommentMapRoundTripUnchanged(t *testing.T) {
+	src := []byte(`# doc comment
+options {
+  recursion no; // inline note
+};
+`)
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out := f.Bytes()
+	if !bytes.Equal(src, out) {
+		t.Fatalf("round-trip mismatch\nIN:\n%q\nOUT:\n%q", string(src), string(out))
+	}
+}
+
+func TestCommentMapAssociatesDocAndLine(t *testing.T) {
+	src := []byte(`# top doc
+# second doc line
+options {
+  recursion no; // trailing
+};
+`)
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	opts := f.TopLevel("options")
+	if len(opts) != 1 {
+		t.Fatalf("expected one options statement, got %d", len(opts))
+	}
+	cm := f.CommentMap()
+	doc := cm.Doc(opts[0])
+	if len(doc) != 2 {
+		t.Fatalf("expected 2 doc comments, got %d: %v", len(doc), doc)
+	}
+	recurse := f.Find(func(s *Stmt) bool { return s.Keyword == "recursion" })
+	if len(recurse) != 1 {
+		t.Fatalf("expected one recursion child, got %d", len(recurse))
+	}
+	line := cm.Line(recurse[0])
+	if line == nil || line.Text != "// trailing" {
+		t.Fatalf("expected trailing line comment, got %v", line)
+	}
+}
+
+func TestFileDeleteRemovesStmtAndDoc(t *testing.T) {
+	src := []byte(`# explains recursion
+options {
+  recursion no;
+};
+`)
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	opts := f.TopLevel("options")[0]
+	if !f.Delete(opts) {
+		t.Fatalf("Delete reported stmt not found")
+	}
+	if len(f.TopLevel("options")) != 0 {
+		t.Fatalf("options statement still present after Delete")
+	}
+	if bytes.Contains(f.Bytes(), []byte("explains recursion")) {
+		t.Fatalf("doc comment survived Delete: %q", f.Bytes())
+	}
+}
+
+// TestLineCommentNotDoubleAttributedAsNextDoc covers a statement's trailing
+// same-line comment immediately followed, with no blank line, by another
+// statement: that comment must be s1's Line comment only, never also s2's
+// Doc comment, and deleting s2 must leave it (and s1) untouched.
+func TestLineCommentNotDoubleAttributedAsNextDoc(t *testing.T) {
+	src := []byte("recursion no; # trailing\nforwarders yes;\n")
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	s1 := f.Find(func(s *Stmt) bool { return s.Keyword == "recursion" })
+	s2 := f.Find(func(s *Stmt) bool { return s.Keyword == "forwarders" })
+	if len(s1) != 1 || len(s2) != 1 {
+		t.Fatalf("expected one recursion and one forwarders stmt, got %d and %d", len(s1), len(s2))
+	}
+	cm := f.CommentMap()
+	line := cm.Line(s1[0])
+	if line == nil || line.Text != "# trailing" {
+		t.Fatalf("expected recursion's trailing comment, got %v", line)
+	}
+	if doc := cm.Doc(s2[0]); len(doc) != 0 {
+		t.Fatalf("expected forwarders to have no doc comments, got %v", doc)
+	}
+	if !f.Delete(s2[0]) {
+		t.Fatalf("Delete reported stmt not found")
+	}
+	if !bytes.Contains(f.Bytes(), []byte("# trailing")) {
+		t.Fatalf("recursion's trailing comment was removed by deleting forwarders: %q", f.Bytes())
+	}
+}
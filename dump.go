@@ -0,0 +1,101 @@
+package namedconf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// rawTextDumpLimit is the longest RawText/Text Fdump prints verbatim before
+// abbreviating it to "...(N bytes)".
+const rawTextDumpLimit = 60
+
+// Fdump writes a recursive, indented dump of n's structure to w: its
+// dynamic type, Pos/EndPos, and exported fields (via reflection, skipping
+// zero-valued ones), with Stmt additionally summarized by its Body's node
+// count rather than dumping every child inline twice. Modeled on
+// cmd/compile/internal/syntax.Fdump.
+//
+// This exists because the parser's tolerant fallbacks -- falling back to a
+// Raw node when buildStmt fails -- otherwise make "why didn't my zone
+// statement become structured?" nearly impossible to debug without hand
+// printing. Tests can call Fdump(os.Stderr, n) on failure to show the
+// actual tree shape, the way go/ast tests use ast.Print.
+func Fdump(w io.Writer, n Node) error {
+	var buf bytes.Buffer
+	d := &dumper{buf: &buf, seen: map[Node]bool{}}
+	d.dump(n, 0)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+type dumper struct {
+	buf  *bytes.Buffer
+	seen map[Node]bool
+}
+
+func (d *dumper) indent(depth int) {
+	for i := 0; i < depth; i++ {
+		d.buf.WriteString("  ")
+	}
+}
+
+func (d *dumper) dump(n Node, depth int) {
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		d.indent(depth)
+		d.buf.WriteString("<nil>\n")
+		return
+	}
+	if d.seen[n] {
+		d.indent(depth)
+		fmt.Fprintf(d.buf, "%T (already dumped, pos=%d)\n", n, n.Pos())
+		return
+	}
+	d.seen[n] = true
+
+	d.indent(depth)
+	fmt.Fprintf(d.buf, "%T pos=%d end=%d\n", n, n.Pos(), n.EndPos())
+
+	v := reflect.ValueOf(n).Elem()
+	t := v.Type()
+	var body []Node
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" { // unexported: position already printed above
+			continue
+		}
+		fv := v.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		if sf.Name == "Body" {
+			body = fv.Interface().([]Node)
+			d.indent(depth + 1)
+			fmt.Fprintf(d.buf, "Body: %d node(s)\n", len(body))
+			continue
+		}
+		d.indent(depth + 1)
+		d.dumpField(sf.Name, fv)
+	}
+	for _, c := range body {
+		d.dump(c, depth+2)
+	}
+}
+
+func (d *dumper) dumpField(name string, fv reflect.Value) {
+	if fv.Kind() == reflect.String {
+		fmt.Fprintf(d.buf, "%s: %q\n", name, abbreviateText(fv.String()))
+		return
+	}
+	fmt.Fprintf(d.buf, "%s: %v\n", name, fv.Interface())
+}
+
+// abbreviateText collapses a long RawText/HeadRaw/Text value so dumps of
+// large statements stay skimmable.
+func abbreviateText(s string) string {
+	if len(s) <= rawTextDumpLimit {
+		return s
+	}
+	return fmt.Sprintf("...(%d bytes)", len(s))
+}
@@ -0,0 +1,55 @@
+package namedconf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// dumpNode is a small Fdump-based test helper: it returns Fdump's output for
+// n, so a failing test can show the actual tree shape instead of a raw
+// struct printf.
+func dumpNode(t *testing.T, n Node) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Fdump(&buf, n); err != nil {
+		t.Fatalf("Fdump: %v", err)
+	}
+	return buf.String()
+}
+
+func TestFdumpShowsStructuredBody(t *testing.T) {
+	src := []byte(`options {
+  recursion no;
+};
+`)
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out := dumpNode(t, f.TopLevel("options")[0])
+	if !strings.Contains(out, `Keyword: "options"`) {
+		t.Fatalf("dump missing Keyword field:\n%s", out)
+	}
+	if !strings.Contains(out, "Body: ") {
+		t.Fatalf("dump missing Body summary:\n%s", out)
+	}
+	if !strings.Contains(out, `Keyword: "recursion"`) {
+		t.Fatalf("dump did not recurse into Body children:\n%s", out)
+	}
+}
+
+func TestFdumpAbbreviatesLongRawText(t *testing.T) {
+	src := []byte("acl trusted { " + strings.Repeat("10.0.0.1; ", 20) + "};\n")
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out := dumpNode(t, f.TopLevel("acl")[0])
+	if strings.Contains(out, "bytes)") == false {
+		t.Fatalf("expected abbreviated RawText in dump:\n%s", out)
+	}
+	if strings.Contains(out, strings.Repeat("10.0.0.1; ", 20)) {
+		t.Fatalf("RawText was not abbreviated:\n%s", out)
+	}
+}
@@ -0,0 +1,237 @@
+package namedconf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MissingIncludesPolicy controls how LoadPackage reacts to an `include`
+// directive whose target file does not exist -- common in production BIND
+// configs, which routinely include optional files.
+type MissingIncludesPolicy int
+
+const (
+	// MissingIncludesError fails LoadPackage outright (the default).
+	MissingIncludesError MissingIncludesPolicy = iota
+	// MissingIncludesWarn skips the include, recording a message in
+	// Package.Warnings.
+	MissingIncludesWarn
+	// MissingIncludesIgnore skips the include silently.
+	MissingIncludesIgnore
+)
+
+// LoadOptions configures LoadPackage.
+type LoadOptions struct {
+	// BaseDir, if set, is treated as a chroot: an `options { directory ...; }`
+	// value and an absolute include path are resolved under BaseDir rather
+	// than against the filesystem root. A relative include path still
+	// resolves against the including file's directory (or its directory
+	// option).
+	BaseDir string
+	// MissingIncludes controls handling of include targets that don't exist.
+	MissingIncludes MissingIncludesPolicy
+}
+
+// CycleError reports an include cycle discovered while loading a Package.
+// Chain lists the absolute paths visited, ending with the path that closes
+// the cycle (which also appears earlier in Chain).
+type CycleError struct {
+	Chain []string
+}
+
+func (e *CycleError) Error() string {
+	return "namedconf: include cycle: " + strings.Join(e.Chain, " -> ")
+}
+
+// Package is a named.conf file together with every file it transitively
+// includes, analogous to go/build's package resolution.
+type Package struct {
+	// Files holds every loaded file, in the order first encountered
+	// (the root file is always Files[0]).
+	Files []*File
+	// Warnings collects messages for includes skipped under
+	// MissingIncludesWarn.
+	Warnings []string
+
+	byPath   map[string]*File
+	resolved map[*Stmt]string
+	fset     *FileSet
+}
+
+// LoadPackage reads rootPath and every file it (transitively) includes,
+// returning the whole tree as a Package. Relative include paths resolve
+// against the including file's directory, unless that file sets
+// `options { directory "..."; }`, in which case they resolve against that
+// directory (optionally rooted at opts.BaseDir).
+func LoadPackage(rootPath string, opts LoadOptions) (*Package, error) {
+	abs, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &Package{
+		byPath:   map[string]*File{},
+		resolved: map[*Stmt]string{},
+		fset:     NewFileSet(),
+	}
+
+	visiting := map[string]bool{}
+	var chain []string
+
+	var load func(path string) error
+	load = func(path string) error {
+		if visiting[path] {
+			return &CycleError{Chain: append(append([]string{}, chain...), path)}
+		}
+		if pkg.byPath[path] != nil {
+			return nil // already loaded via another include path (a diamond, not a cycle)
+		}
+		visiting[path] = true
+		chain = append(chain, path)
+		defer func() {
+			visiting[path] = false
+			chain = chain[:len(chain)-1]
+		}()
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f, err := ParseWithFileSet(pkg.fset, path, b)
+		if err != nil {
+			return err
+		}
+		pkg.byPath[path] = f
+		pkg.Files = append(pkg.Files, f)
+
+		dir := filepath.Dir(path)
+		if d := directoryOption(f); d != "" {
+			dir = resolveIncludeDir(opts.BaseDir, d)
+		}
+
+		for _, inc := range f.Find(func(s *Stmt) bool { return s.Keyword == "include" }) {
+			target := headValue(inc)
+			if target == "" {
+				continue
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			} else if opts.BaseDir != "" {
+				target = filepath.Join(opts.BaseDir, target)
+			}
+			target, err = filepath.Abs(target)
+			if err != nil {
+				return err
+			}
+			pkg.resolved[inc] = target
+
+			if _, statErr := os.Stat(target); statErr != nil {
+				switch opts.MissingIncludes {
+				case MissingIncludesIgnore:
+					continue
+				case MissingIncludesWarn:
+					pkg.Warnings = append(pkg.Warnings, fmt.Sprintf("include %q: %v", target, statErr))
+					continue
+				default:
+					return fmt.Errorf("include %q: %w", target, statErr)
+				}
+			}
+			if err := load(target); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := load(abs); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// directoryOption returns the value of f's top-level
+// `options { directory "..."; }` setting, or "" if absent.
+func directoryOption(f *File) string {
+	for _, opts := range f.TopLevel("options") {
+		for _, n := range opts.Body {
+			if s, ok := n.(*Stmt); ok && s.Keyword == "directory" {
+				return headValue(s)
+			}
+		}
+	}
+	return ""
+}
+
+// resolveIncludeDir applies an options{} `directory` setting under an
+// optional chroot base.
+func resolveIncludeDir(baseDir, directory string) string {
+	if baseDir != "" {
+		return filepath.Join(baseDir, directory)
+	}
+	return directory
+}
+
+// headValue returns s.HeadRaw with its leading keyword and any surrounding
+// quotes stripped, e.g. "/etc/named.rfc1912.zones" for
+// `include "/etc/named.rfc1912.zones"`.
+func headValue(s *Stmt) string {
+	h := strings.TrimSpace(s.HeadRaw)
+	h = strings.TrimPrefix(h, s.Keyword)
+	return strings.Trim(strings.TrimSpace(h), `"`)
+}
+
+// Walk calls fn for every file in the package, root first.
+func (p *Package) Walk(fn func(*File)) {
+	for _, f := range p.Files {
+		fn(f)
+	}
+}
+
+// Find searches every file in the package and returns all statements
+// matching pred.
+func (p *Package) Find(pred func(*Stmt) bool) []*Stmt {
+	var out []*Stmt
+	for _, f := range p.Files {
+		out = append(out, f.Find(pred)...)
+	}
+	return out
+}
+
+// Resolve returns the File an `include` statement (as found via Find or
+// Walk) resolved to, or nil if stmt isn't an include this Package loaded --
+// e.g. because its target was missing and MissingIncludes tolerated that.
+func (p *Package) Resolve(stmt *Stmt) *File {
+	path, ok := p.resolved[stmt]
+	if !ok {
+		return nil
+	}
+	return p.byPath[path]
+}
+
+// Save writes back every modified file in the package, atomically, to its
+// original path.
+func (p *Package) Save() error {
+	for _, f := range p.Files {
+		if !fileModified(f) {
+			continue
+		}
+		if err := f.Save(""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileModified(f *File) bool {
+	modified := false
+	f.Walk(func(n Node) bool {
+		if s, ok := n.(*Stmt); ok && s.Modified {
+			modified = true
+			return false
+		}
+		return true
+	})
+	return modified
+}
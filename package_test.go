@@ -0,0 +1,81 @@
+package namedconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPackageFollowsIncludes(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "named.conf")
+	zones := filepath.Join(dir, "zones.conf")
+
+	mustWrite(t, root, `options { directory "`+dir+`"; };
+include "zones.conf";
+`)
+	mustWrite(t, zones, `zone "example.com" IN { type master; file "db.example.com"; };
+`)
+
+	pkg, err := LoadPackage(root, LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadPackage: %v", err)
+	}
+	if len(pkg.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(pkg.Files))
+	}
+	if len(pkg.Find(func(s *Stmt) bool { return s.Keyword == "zone" })) != 1 {
+		t.Fatalf("expected to find the zone statement from the included file")
+	}
+
+	includes := pkg.Find(func(s *Stmt) bool { return s.Keyword == "include" })
+	if len(includes) != 1 {
+		t.Fatalf("expected 1 include statement, got %d", len(includes))
+	}
+	resolved := pkg.Resolve(includes[0])
+	if resolved == nil || resolved != pkg.Files[1] {
+		t.Fatalf("Resolve did not return the included file")
+	}
+}
+
+func TestLoadPackageDetectsCycle(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.conf")
+	b := filepath.Join(dir, "b.conf")
+
+	mustWrite(t, a, `include "b.conf";`)
+	mustWrite(t, b, `include "a.conf";`)
+
+	_, err := LoadPackage(a, LoadOptions{})
+	if err == nil {
+		t.Fatal("expected a CycleError")
+	}
+	if _, ok := err.(*CycleError); !ok {
+		t.Fatalf("expected *CycleError, got %T: %v", err, err)
+	}
+}
+
+func TestLoadPackageMissingIncludePolicies(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "named.conf")
+	mustWrite(t, root, `include "missing.conf";`)
+
+	if _, err := LoadPackage(root, LoadOptions{}); err == nil {
+		t.Fatal("expected missing include to error by default")
+	}
+
+	pkg, err := LoadPackage(root, LoadOptions{MissingIncludes: MissingIncludesWarn})
+	if err != nil {
+		t.Fatalf("LoadPackage with Warn policy: %v", err)
+	}
+	if len(pkg.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(pkg.Warnings))
+	}
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -3,234 +3,228 @@ package namedconf
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"strings"
-	"unicode"
+
+	"github.com/dlukt/namedconf/scanner"
 )
 
 type parser struct {
-	src []byte
+	src    []byte
+	file   *posFile
+	errors []*ParseError
+}
+
+// errorf records a tolerated parse failure at the given absolute byte
+// offset, resolved against p.file so File.Errors() reports a useful
+// Position.
+func (p *parser) errorf(offset int, format string, args ...any) {
+	pos := Pos(p.file.base + offset)
+	p.errors = append(p.errors, &ParseError{Pos: p.file.position(pos), Msg: fmt.Sprintf(format, args...)})
 }
 
-// parseRange splits src[start:end] into top-level nodes (Raw and Stmt), recursively parsing block bodies.
+// parseRange splits src[start:end] into top-level nodes (Raw and Stmt),
+// recursively parsing block bodies. It drives a scanner.Scanner over the
+// range to find brace depth and statement-terminating semicolons, rather
+// than re-deriving comment/string/brace recognition by hand -- the scanner
+// is also what gives errorf column-accurate positions for malformed
+// strings and block comments.
 func (p *parser) parseRange(start, end int) ([]Node, error) {
 	var nodes []Node
-	i := start
-	last := start // start index of the next Raw segment if any
+	last := start
 
-	depth := 0
-	inSlashStar := false
-	inLine := false
-	inString := false
-
-	for i < end {
-		c := p.src[i]
-
-		// End-of-line resets line comments
-		if inLine {
-			if c == '\n' {
-				inLine = false
-			}
-			i++
-			continue
-		}
-
-		// Inside block comment
-		if inSlashStar {
-			if c == '*' && i+1 < end && p.src[i+1] == '/' {
-				inSlashStar = false
-				i += 2
-				continue
-			}
-			i++
-			continue
+	for i := start; i < end; i++ {
+		if p.src[i] == '\n' {
+			p.file.addLine(i + 1)
 		}
+	}
 
-		// Inside string (double quotes per BIND)
-		if inString {
-			if c == '\\' { // escape next
-				if i+1 < end {
-					i += 2
-					continue
-				}
-				i++
-				continue
-			}
-			if c == '"' {
-				inString = false
-			}
-			i++
-			continue
-		}
+	var sc scanner.Scanner
+	sc.Init(p.src[start:end], nil, func(pos scanner.Pos, msg string) {
+		p.errorf(start+int(pos), "%s", msg)
+	})
 
-		// Enter comments/strings
-		if c == '/' && i+1 < end {
-			if p.src[i+1] == '*' {
-				inSlashStar = true
-				i += 2
-				continue
-			}
-			if p.src[i+1] == '/' {
-				inLine = true
-				i += 2
-				continue
-			}
-		}
-		if c == '#' {
-			inLine = true
-			i++
-			continue
-		}
-		if c == '"' {
-			inString = true
-			i++
-			continue
+	depth := 0
+	for {
+		pos, tok, _ := sc.Scan()
+		if tok == scanner.EOF {
+			break
 		}
-
-		// Track braces only outside strings/comments
-		if c == '{' {
+		abs := start + int(pos)
+		switch tok {
+		case scanner.LBRACE:
 			depth++
-			i++
-			continue
-		}
-		if c == '}' {
+		case scanner.RBRACE:
 			if depth > 0 {
 				depth--
 			}
-			i++
-			continue
-		}
-
-		// Statement boundary: semicolon at top level
-		if c == ';' && depth == 0 {
-			// capture statement segment [stmtStart: i+1]
-			// preceding Raw (trivia) is [last:stmtStart)
-			// find stmtStart by scanning backwards from current to previous non-space after last.
-			// However, we assume statement starts at last non-trivia point; so segment is [lastStmtStart, i+1]
-			// To keep it simple and robust, cut trivia+stmt into Raw+Stmt where Raw is [last:stmtStart), Stmt is [stmtStart:i+1]
-			stmtStart := findStmtStart(p.src, last, i)
+		case scanner.SEMI:
+			if depth != 0 {
+				continue
+			}
+			stmtEnd := abs + 1
+			stmtStart := findStmtStart(p.src, last, abs)
 			if stmtStart > last {
-				nodes = append(nodes, &Raw{Text: string(p.src[last:stmtStart]), start: last, end: stmtStart})
+				nodes = append(nodes, p.splitTrivia(last, stmtStart)...)
 			}
-			seg := p.src[stmtStart : i+1]
+			seg := p.src[stmtStart:stmtEnd]
 			st, err := p.buildStmt(seg, stmtStart)
 			if err != nil {
-				// Be tolerant: if we fail, fall back to Raw segment to preserve bytes
-				nodes = append(nodes, &Raw{Text: string(seg), start: stmtStart, end: i + 1})
+				// Be tolerant: if we fail, fall back to Raw segment to preserve bytes,
+				// but record why so callers can tell a tolerated region from a clean parse.
+				p.errorf(stmtStart, "%v", err)
+				nodes = append(nodes, p.newRaw(stmtStart, stmtEnd))
 			} else {
 				nodes = append(nodes, st)
 			}
-			last = i + 1
-			i++
-			continue
+			last = stmtEnd
 		}
-
-		i++
 	}
 
-	// Trailing Raw
+	// Trailing trivia. Text after the last ';' that is not just whitespace
+	// and comments (e.g. a statement missing its ';') stays one opaque Raw.
 	if last < end {
-		nodes = append(nodes, &Raw{Text: string(p.src[last:end]), start: last, end: end})
+		if findStmtStart(p.src, last, end) < end {
+			nodes = append(nodes, p.newRaw(last, end))
+		} else {
+			nodes = append(nodes, p.splitTrivia(last, end)...)
+		}
 	}
 
+	linkComments(nodes)
 	return nodes, nil
 }
 
-// findStmtStart walks back from pos to find a likely start (skip preceding whitespace/comments that we kept in Raw).
+// splitTrivia splits the trivia span [start, end) -- whitespace and
+// comments between two clean statements -- into individual Whitespace and
+// Comment nodes, so comments can be addressed and associated with their
+// statement (see CommentMap) instead of living inside an opaque blob. It
+// scans the span with scanner.Scanner and treats the gaps between COMMENT
+// tokens as Whitespace.
+//
+// It is only used for trivia between *clean* statements: a region the
+// parser couldn't make sense of is still preserved as a single opaque Raw,
+// see the tolerant-fallback paths above and in buildStmt.
+func (p *parser) splitTrivia(start, end int) []Node {
+	var out []Node
+	wsStart := start
+
+	var sc scanner.Scanner
+	sc.Init(p.src[start:end], nil, nil)
+	for {
+		pos, tok, lit := sc.Scan()
+		if tok == scanner.EOF {
+			break
+		}
+		if tok != scanner.COMMENT {
+			continue
+		}
+		cstart := start + int(pos)
+		cend := cstart + len(lit)
+		if cstart > wsStart {
+			out = append(out, p.newWhitespace(wsStart, cstart))
+		}
+		out = append(out, p.newComment(cstart, cend, commentKind(lit)))
+		wsStart = cend
+	}
+	if end > wsStart {
+		out = append(out, p.newWhitespace(wsStart, end))
+	}
+	return out
+}
+
+// commentKind classifies a comment's literal text by its opening delimiter.
+func commentKind(lit string) CommentKind {
+	switch {
+	case strings.HasPrefix(lit, "//"):
+		return SlashSlashComment
+	case strings.HasPrefix(lit, "/*"):
+		return BlockComment
+	default:
+		return HashComment
+	}
+}
+
+func (p *parser) newWhitespace(start, end int) *Whitespace {
+	return &Whitespace{
+		Text:  string(p.src[start:end]),
+		start: start, end: end,
+		pos: Pos(p.file.base + start), endP: Pos(p.file.base + end),
+	}
+}
+
+func (p *parser) newComment(start, end int, kind CommentKind) *Comment {
+	return &Comment{
+		Text: string(p.src[start:end]), Kind: kind,
+		start: start, end: end,
+		pos: Pos(p.file.base + start), endP: Pos(p.file.base + end),
+	}
+}
+
+// newRaw builds a Raw node covering the absolute byte range [start, end),
+// resolving Pos values against the parser's file.
+func (p *parser) newRaw(start, end int) *Raw {
+	return &Raw{
+		Text:  string(p.src[start:end]),
+		start: start, end: end,
+		pos: Pos(p.file.base + start), endP: Pos(p.file.base + end),
+	}
+}
+
+// findStmtStart scans forward from last to find where the statement itself
+// begins, skipping any leading comments (via the scanner, so #, //, and
+// /* */ are all recognized the same way splitTrivia recognizes them) --
+// so that splitTrivia (called on [last, stmtStart)) always gets the full
+// leading trivia, including doc comments immediately above the statement,
+// rather than having them swallowed into the statement's own
+// HeadRaw/RawText.
 func findStmtStart(src []byte, last, pos int) int {
-	// naive: statement starts at first non-space from 'last' forward
-	i := last
-	for i < pos && isSpace(src[i]) {
-		i++
+	var sc scanner.Scanner
+	sc.Init(src[last:pos], nil, nil)
+	for {
+		p, tok, _ := sc.Scan()
+		if tok == scanner.EOF {
+			return pos
+		}
+		if tok != scanner.COMMENT {
+			return last + int(p)
+		}
 	}
-	return i
 }
 
+// buildStmt decodes seg (a single statement's bytes, ending in ';') into a
+// Stmt, using a scanner.Scanner to find the top-level '{'/'}' pair so that
+// braces inside comments or quoted strings are never mistaken for a block.
 func (p *parser) buildStmt(seg []byte, absStart int) (*Stmt, error) {
-	s := &Stmt{RawText: string(seg), start: absStart, end: absStart + len(seg)}
+	s := &Stmt{
+		RawText: string(seg), start: absStart, end: absStart + len(seg),
+		pos: Pos(p.file.base + absStart), endP: Pos(p.file.base + absStart + len(seg)),
+	}
 
-	// Extract top-level head vs. body: find first '{' at depth 0.
-	// We must respect comments/strings again.
-	i := 0
+	braceOpen, braceClose := -1, -1
 	depth := 0
-	inSlashStar := false
-	inLine := false
-	inString := false
-	braceOpen := -1
-	braceClose := -1
 
-	for i < len(seg) {
-		c := seg[i]
-		if inLine {
-			if c == '\n' {
-				inLine = false
-			}
-			i++
-			continue
-		}
-		if inSlashStar {
-			if c == '*' && i+1 < len(seg) && seg[i+1] == '/' {
-				inSlashStar = false
-				i += 2
-				continue
-			}
-			i++
-			continue
-		}
-		if inString {
-			if c == '\\' {
-				if i+1 < len(seg) {
-					i += 2
-					continue
-				}
-			}
-			if c == '"' {
-				inString = false
-			}
-			i++
-			continue
-		}
-		if c == '/' && i+1 < len(seg) {
-			if seg[i+1] == '*' {
-				inSlashStar = true
-				i += 2
-				continue
-			}
-			if seg[i+1] == '/' {
-				inLine = true
-				i += 2
-				continue
-			}
-		}
-		if c == '#' {
-			inLine = true
-			i++
-			continue
-		}
-		if c == '"' {
-			inString = true
-			i++
-			continue
+	var sc scanner.Scanner
+	sc.Init(seg, nil, nil)
+	for {
+		pos, tok, _ := sc.Scan()
+		if tok == scanner.EOF {
+			break
 		}
-
-		if c == '{' {
+		switch tok {
+		case scanner.LBRACE:
 			if depth == 0 && braceOpen < 0 {
-				braceOpen = i
+				braceOpen = int(pos)
 			}
 			depth++
-			i++
-			continue
-		}
-		if c == '}' {
+		case scanner.RBRACE:
 			if depth > 0 {
 				depth--
 			}
 			if depth == 0 && braceOpen >= 0 && braceClose < 0 {
-				braceClose = i
+				braceClose = int(pos)
 			}
-			i++
-			continue
 		}
-		i++
 	}
 
 	// Head is before braceOpen (if any) else before final ';'
@@ -256,7 +250,8 @@ func (p *parser) buildStmt(seg []byte, absStart int) (*Stmt, error) {
 			bodyNodes, err := p.parseRange(absStart+bodyStart, absStart+bodyEnd)
 			if err != nil {
 				// Tolerant: keep as raw body
-				s.Body = []Node{&Raw{Text: string(bodySrc), start: absStart + bodyStart, end: absStart + bodyEnd}}
+				p.errorf(absStart+bodyStart, "%v", err)
+				s.Body = []Node{p.newRaw(absStart+bodyStart, absStart+bodyEnd)}
 			} else {
 				// Adjust body nodes to belong to this statement (their start/end are absolute already)
 				s.Body = bodyNodes
@@ -280,61 +275,22 @@ func (p *parser) buildStmt(seg []byte, absStart int) (*Stmt, error) {
 
 func isSpace(b byte) bool { return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f' }
 
-// firstIdent extracts the first identifier-like token from s.
+// firstIdent extracts the first identifier-like token from s (a Stmt's
+// HeadRaw), skipping any leading comments, via the scanner. STRING literals
+// come back with their quotes already stripped.
 func firstIdent(s string) string {
-	// Skip leading space and comment openers quickly.
-	i := 0
-	for i < len(s) {
-		r := rune(s[i])
-		if unicode.IsSpace(r) {
-			i++
-			continue
-		}
-		// Skip comments entirely
-		if s[i] == '#' {
-			// to end of line
-			j := i + 1
-			for j < len(s) && s[j] != '\n' {
-				j++
-			}
-			i = j + 1
-			continue
+	var sc scanner.Scanner
+	sc.Init([]byte(s), nil, nil)
+	for {
+		_, tok, lit := sc.Scan()
+		if tok == scanner.EOF {
+			return ""
 		}
-		if s[i] == '/' && i+1 < len(s) && (s[i+1] == '/' || s[i+1] == '*') {
-			if s[i+1] == '/' {
-				j := i + 2
-				for j < len(s) && s[j] != '\n' {
-					j++
-				}
-				i = j + 1
-				continue
-			}
-			// /* */
-			j := i + 2
-			for j+1 < len(s) && !(s[j] == '*' && s[j+1] == '/') {
-				j++
-			}
-			if j+1 < len(s) {
-				j += 2
-			}
-			i = j
+		if tok == scanner.COMMENT {
 			continue
 		}
-		break
-	}
-	// Collect until space/{/;/
-	start := i
-	for i < len(s) {
-		c := s[i]
-		if isSpace(c) || c == '{' || c == ';' {
-			break
-		}
-		i++
+		return lit
 	}
-	tok := strings.TrimSpace(s[start:i])
-	// Unquote if string literal
-	tok = strings.Trim(tok, "\"")
-	return tok
 }
 
 func trimRightSpace(s string) string {
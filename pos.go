@@ -0,0 +1,141 @@
+package namedconf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Pos is an opaque source position, modeled on go/token.Pos. The zero value
+// (NoPos) means "no position is associated". Pos values are only meaningful
+// relative to the FileSet that produced them.
+type Pos int
+
+// NoPos is the zero Pos; File.Position(NoPos) returns the zero Position.
+const NoPos Pos = 0
+
+// Position describes a resolved source location.
+type Position struct {
+	Filename string
+	Offset   int // byte offset, starting at 0
+	Line     int // line number, starting at 1
+	Column   int // column number (byte-based), starting at 1
+}
+
+// IsValid reports whether the position is valid.
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// String formats the position the way compilers conventionally do, e.g.
+// "named.conf:42:7".
+func (p Position) String() string {
+	s := p.Filename
+	if s == "" {
+		s = "-"
+	}
+	if p.IsValid() {
+		s += fmt.Sprintf(":%d", p.Line)
+		if p.Column > 0 {
+			s += fmt.Sprintf(":%d", p.Column)
+		}
+	}
+	return s
+}
+
+// posFile tracks the line-start offsets for a single file registered with a
+// FileSet, so byte offsets can be resolved to line/column pairs.
+type posFile struct {
+	name  string
+	base  int // Pos of the file's first byte
+	size  int
+	lines []int // byte offsets of line starts; lines[0] is always 0
+}
+
+// addLine records the offset of a line start (the byte following a '\n').
+// Offsets must be added in increasing order; out-of-order or duplicate
+// offsets are ignored.
+func (f *posFile) addLine(offset int) {
+	if n := len(f.lines); (n == 0 || f.lines[n-1] < offset) && offset < f.size {
+		f.lines = append(f.lines, offset)
+	}
+}
+
+func (f *posFile) position(pos Pos) Position {
+	offset := int(pos) - f.base
+	if offset < 0 {
+		offset = 0
+	}
+	// lines[i] is the offset of the first byte of line i+1 (lines[0]==0 is
+	// the start of line 1), so the line containing offset is the count of
+	// line starts <= offset.
+	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > offset })
+	line := i
+	lineStart := 0
+	if i > 0 {
+		lineStart = f.lines[i-1]
+	}
+	return Position{Filename: f.name, Offset: offset, Line: line, Column: offset - lineStart + 1}
+}
+
+// FileSet tracks line offsets across one or more source files so that Pos
+// values can be resolved back into (filename, line, column, offset) tuples,
+// analogous to go/token.FileSet.
+type FileSet struct {
+	files []*posFile
+	base  int // next base to hand out; starts at 1 so Pos(0) stays NoPos
+}
+
+// NewFileSet creates a new, empty FileSet.
+func NewFileSet() *FileSet {
+	return &FileSet{base: 1}
+}
+
+// addFile registers a new file of the given size and returns its posFile,
+// whose addLine method the parser calls for each newline in the source.
+func (s *FileSet) addFile(filename string, size int) *posFile {
+	f := &posFile{name: filename, base: s.base, lines: []int{0}, size: size}
+	s.files = append(s.files, f)
+	s.base += size + 1 // +1 so consecutive files never share a Pos
+	return f
+}
+
+func (s *FileSet) fileFor(pos Pos) *posFile {
+	for _, f := range s.files {
+		if int(pos) >= f.base && int(pos) <= f.base+f.size {
+			return f
+		}
+	}
+	return nil
+}
+
+// Position resolves pos to a Position. It returns the zero Position if pos
+// is NoPos or does not belong to any file in the set.
+func (s *FileSet) Position(pos Pos) Position {
+	if pos == NoPos || s == nil {
+		return Position{}
+	}
+	if f := s.fileFor(pos); f != nil {
+		return f.position(pos)
+	}
+	return Position{}
+}
+
+// File returns the name of the file pos belongs to, or "" if unknown.
+func (s *FileSet) File(pos Pos) string {
+	if f := s.fileFor(pos); f != nil {
+		return f.name
+	}
+	return ""
+}
+
+// ParseError describes a location where the parser could not make sense of
+// a statement and fell back to preserving it as Raw text.
+type ParseError struct {
+	Pos Position
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	if e.Pos.IsValid() || e.Pos.Filename != "" {
+		return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+	}
+	return e.Msg
+}
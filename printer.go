@@ -0,0 +1,254 @@
+package namedconf
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+)
+
+// BracePlacement controls where a block statement's opening brace goes.
+type BracePlacement int
+
+const (
+	// SameLine places '{' at the end of the head line: "zone \"x\" {".
+	SameLine BracePlacement = iota
+	// NextLine places '{' alone on the line following the head.
+	NextLine
+)
+
+// Printer controls how regenerated (Modified) nodes are rendered, modeled
+// on go/printer.Config. The zero-value Printer reproduces the
+// package's historical formatting exactly, so File.Bytes() and File.Save()
+// need no style configuration to keep working as before.
+//
+// Printer only ever applies these style options to statements it actually
+// regenerates: a Stmt with Modified==false still emits its original
+// RawText verbatim, so a file with a handful of edits stays diff-clean
+// around everything else. Use Canonical to force every node through the
+// style options regardless of Modified, e.g. for a gofmt-style formatter.
+type Printer struct {
+	// Indent is repeated once per nesting level. "" means two spaces,
+	// matching the package's original hard-coded indentation.
+	Indent string
+	// BracePlacement controls '{' placement for regenerated blocks.
+	BracePlacement BracePlacement
+	// AlignBodyValues pads a block's simple "key value;" children so their
+	// values line up in a column, e.g. "recursion   no;". It only affects
+	// children that are themselves being regenerated.
+	AlignBodyValues bool
+	// MaxBlankLines caps consecutive blank lines kept in Raw trivia. <= 0
+	// means unlimited (the original behavior: Raw is never touched).
+	MaxBlankLines int
+	// SortBodyBy, if set, stably reorders a block's Stmt children (Raw
+	// trivia keeps its original slot). Useful for e.g. sorting acl entries.
+	SortBodyBy func(a, b *Stmt) bool
+
+	canonical bool
+}
+
+// Canonical returns a copy of pr that regenerates every node regardless of
+// Modified -- a formatter/gofmt-equivalent preset.
+func (pr Printer) Canonical() Printer {
+	pr.canonical = true
+	return pr
+}
+
+// Fprint writes f to w using pr's style options.
+func (pr Printer) Fprint(w io.Writer, f *File) error {
+	var buf bytes.Buffer
+	for _, n := range f.Nodes {
+		pr.writeNode(&buf, n)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// FprintNode writes a single node to w using pr's style options.
+func (pr Printer) FprintNode(w io.Writer, n Node) error {
+	var buf bytes.Buffer
+	pr.writeNode(&buf, n)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (pr Printer) indentUnit() string {
+	if pr.Indent == "" {
+		return "  "
+	}
+	return pr.Indent
+}
+
+func (pr Printer) writeNode(buf *bytes.Buffer, n Node) {
+	switch v := n.(type) {
+	case *Raw:
+		buf.WriteString(pr.renderRaw(v.Text))
+	case *Whitespace:
+		buf.WriteString(pr.renderRaw(v.Text))
+	case *Comment:
+		buf.WriteString(v.Text)
+	case *Stmt:
+		pr.writeStmt(buf, v)
+	default:
+		n.writeTo(buf)
+	}
+}
+
+func (pr Printer) renderRaw(text string) string {
+	if pr.MaxBlankLines <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	blank := 0
+	for i, ln := range lines {
+		// The first and last segments are the tail of the preceding line and
+		// the head of the following one, not lines of their own.
+		if i > 0 && i < len(lines)-1 && strings.TrimSpace(ln) == "" {
+			blank++
+			if blank > pr.MaxBlankLines {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		out = append(out, ln)
+	}
+	return strings.Join(out, "\n")
+}
+
+// writeStmt regenerates s per pr's style options, or -- unless pr is
+// Canonical -- emits s.RawText verbatim when s.Modified is false.
+func (pr Printer) writeStmt(buf *bytes.Buffer, s *Stmt) {
+	if !pr.canonical && !s.Modified && s.RawText != "" {
+		buf.WriteString(s.RawText)
+		return
+	}
+
+	if !s.HasBlock {
+		if s.HeadRaw == "" {
+			buf.WriteString(s.RawText)
+			return
+		}
+		buf.WriteString(trimRightSpace(s.HeadRaw))
+		buf.WriteByte(';')
+		return
+	}
+
+	head := trimRightSpace(s.HeadRaw)
+	if head == "" {
+		head = s.Keyword
+	}
+	buf.WriteString(head)
+	if pr.BracePlacement == NextLine {
+		buf.WriteByte('\n')
+		buf.WriteString("{")
+	} else {
+		buf.WriteString(" {")
+	}
+
+	body := s.Body
+	if pr.SortBodyBy != nil {
+		body = sortedBody(body, pr.SortBodyBy)
+	}
+
+	if len(body) > 0 {
+		buf.WriteByte('\n')
+		padTo := 0
+		if pr.AlignBodyValues {
+			padTo = pr.alignWidth(body)
+		}
+		indent := pr.indentUnit()
+		for _, n := range body {
+			var inner bytes.Buffer
+			if !pr.writeAligned(&inner, n, padTo) {
+				pr.writeNode(&inner, n)
+			}
+			lines := bytes.Split(inner.Bytes(), []byte("\n"))
+			for i, ln := range lines {
+				if i < len(lines)-1 {
+					buf.WriteString(indent)
+					buf.Write(ln)
+					buf.WriteByte('\n')
+				} else if len(ln) > 0 {
+					buf.WriteString(indent)
+					buf.Write(ln)
+				}
+			}
+		}
+		if last := buf.Bytes(); len(last) == 0 || last[len(last)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	buf.WriteString("}")
+	buf.WriteString(";")
+}
+
+// alignWidth returns the widest keyword among body's simple, regenerated
+// "key value;" children, for column alignment.
+func (pr Printer) alignWidth(body []Node) int {
+	width := 0
+	for _, n := range body {
+		if s, ok := n.(*Stmt); ok && !s.HasBlock && (s.Modified || pr.canonical) {
+			if k, _, ok := simpleKeyValue(s.HeadRaw); ok && len(k) > width {
+				width = len(k)
+			}
+		}
+	}
+	return width
+}
+
+// writeAligned writes n padded to padTo if it's a simple, regenerated
+// "key value;" statement, and reports whether it did so.
+func (pr Printer) writeAligned(buf *bytes.Buffer, n Node, padTo int) bool {
+	if padTo == 0 {
+		return false
+	}
+	s, ok := n.(*Stmt)
+	if !ok || s.HasBlock || !(s.Modified || pr.canonical) {
+		return false
+	}
+	k, v, ok := simpleKeyValue(s.HeadRaw)
+	if !ok {
+		return false
+	}
+	buf.WriteString(k)
+	buf.WriteString(strings.Repeat(" ", padTo-len(k)+1))
+	buf.WriteString(v)
+	buf.WriteByte(';')
+	return true
+}
+
+// simpleKeyValue splits a "key value" head into its two parts.
+func simpleKeyValue(head string) (key, value string, ok bool) {
+	head = trimRightSpace(head)
+	i := strings.IndexAny(head, " \t")
+	if i < 0 {
+		return "", "", false
+	}
+	return head[:i], strings.TrimSpace(head[i+1:]), true
+}
+
+// sortedBody stably reorders body's *Stmt children by less, leaving Raw
+// trivia anchored at its original index.
+func sortedBody(body []Node, less func(a, b *Stmt) bool) []Node {
+	var idx []int
+	var stmts []*Stmt
+	for i, n := range body {
+		if s, ok := n.(*Stmt); ok {
+			idx = append(idx, i)
+			stmts = append(stmts, s)
+		}
+	}
+	sort.SliceStable(stmts, func(i, j int) bool { return less(stmts[i], stmts[j]) })
+	out := append([]Node(nil), body...)
+	for k, i := range idx {
+		out[i] = stmts[k]
+	}
+	return out
+}
+
+// defaultPrinter is used by Stmt.writeTo/Raw.writeTo so File.Bytes, File.Save,
+// and File.WriteTo keep their historical output without needing to know
+// about Printer at all.
+var defaultPrinter = Printer{}
@@ -0,0 +1,45 @@
+package namedconf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrinterZeroValueMatchesLegacyOutput(t *testing.T) {
+	src := []byte(`options {
+  recursion no;
+};
+`)
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	f.TopLevel("options")[0].ReplaceHead(`options`)
+
+	legacy := f.Bytes()
+
+	var viaPrinter bytes.Buffer
+	if err := (Printer{}).Fprint(&viaPrinter, f); err != nil {
+		t.Fatalf("Fprint: %v", err)
+	}
+	if string(legacy) != viaPrinter.String() {
+		t.Fatalf("zero-value Printer diverged from File.Bytes:\nlegacy: %q\nprinter: %q", legacy, viaPrinter.String())
+	}
+}
+
+func TestPrinterCanonicalRewritesUnmodifiedNodes(t *testing.T) {
+	src := []byte(`options{recursion no;};
+`)
+	f, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := (Printer{}).Canonical().Fprint(&buf, f); err != nil {
+		t.Fatalf("Fprint: %v", err)
+	}
+	want := "options {\n  recursion no;\n};\n"
+	if buf.String() != want {
+		t.Fatalf("canonical output = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,12 @@
+package namedconf
+
+import "github.com/dlukt/namedconf/scanner"
+
+// Tokens lexes src with the scanner package and returns its full token
+// stream. Parse and ParseFile drive the same scanner internally (see
+// parser.go); this is for callers that want BIND's raw tokens directly --
+// for a linter or highlighter, say -- without depending on the scanner
+// package themselves.
+func Tokens(src []byte) []scanner.TokenInfo {
+	return scanner.Tokenize(src)
+}
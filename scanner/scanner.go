@@ -0,0 +1,200 @@
+package scanner
+
+import "strings"
+
+// ErrorHandler is called for malformed input (e.g. an unterminated string or
+// block comment) at the position where the problem was detected.
+type ErrorHandler func(pos Pos, msg string)
+
+// Scanner turns named.conf source bytes into a stream of tokens. The zero
+// value is not ready to use; call Init first.
+type Scanner struct {
+	src    []byte
+	offset int
+	file   *File // optional; nil disables line/column tracking
+	err    ErrorHandler
+}
+
+// Init prepares s to scan src. file may be nil if line/column tracking is
+// not needed; errHandler may be nil to silently tolerate malformed input.
+func (s *Scanner) Init(src []byte, file *File, errHandler ErrorHandler) {
+	s.src = src
+	s.offset = 0
+	s.file = file
+	s.err = errHandler
+}
+
+func (s *Scanner) error(pos Pos, msg string) {
+	if s.err != nil {
+		s.err(pos, msg)
+	}
+}
+
+func (s *Scanner) peek(ahead int) byte {
+	if s.offset+ahead < len(s.src) {
+		return s.src[s.offset+ahead]
+	}
+	return 0
+}
+
+// skipWhitespace advances past spaces, tabs, and newlines, recording line
+// starts as it goes.
+func (s *Scanner) skipWhitespace() {
+	for s.offset < len(s.src) {
+		switch s.src[s.offset] {
+		case ' ', '\t', '\r':
+			s.offset++
+		case '\n':
+			s.offset++
+			s.file.AddLine(s.offset)
+		default:
+			return
+		}
+	}
+}
+
+// isDelimiter reports whether b ends a bare word (IDENT/NUMBER/INCLUDE).
+func isDelimiter(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n', '{', '}', ';', '"', '#':
+		return true
+	}
+	return false
+}
+
+// Scan returns the next token, its starting position, and its literal text
+// (quotes stripped for STRING, comment markers kept for COMMENT). Scan
+// returns (pos, EOF, "") once the source is exhausted.
+func (s *Scanner) Scan() (pos Pos, tok Token, lit string) {
+	s.skipWhitespace()
+	pos = Pos(s.offset)
+	if s.offset >= len(s.src) {
+		return pos, EOF, ""
+	}
+
+	c := s.src[s.offset]
+	switch {
+	case c == '{':
+		s.offset++
+		return pos, LBRACE, "{"
+	case c == '}':
+		s.offset++
+		return pos, RBRACE, "}"
+	case c == ';':
+		s.offset++
+		return pos, SEMI, ";"
+	case c == '"':
+		lit = s.scanString(pos)
+		return pos, STRING, lit
+	case c == '#':
+		return pos, COMMENT, s.scanLineComment()
+	case c == '/' && s.peek(1) == '/':
+		return pos, COMMENT, s.scanLineComment()
+	case c == '/' && s.peek(1) == '*':
+		return pos, COMMENT, s.scanBlockComment(pos)
+	default:
+		lit = s.scanWord()
+		return pos, classify(lit), lit
+	}
+}
+
+// scanString consumes a double-quoted string, honoring backslash escapes,
+// and returns its contents with the surrounding quotes stripped.
+func (s *Scanner) scanString(start Pos) string {
+	startOffset := s.offset
+	s.offset++ // opening quote
+	for s.offset < len(s.src) {
+		c := s.src[s.offset]
+		if c == '\\' && s.offset+1 < len(s.src) {
+			s.offset += 2
+			continue
+		}
+		if c == '"' {
+			s.offset++
+			return string(s.src[startOffset+1 : s.offset-1])
+		}
+		if c == '\n' {
+			s.file.AddLine(s.offset + 1)
+		}
+		s.offset++
+	}
+	s.error(start, "string literal not terminated")
+	return string(s.src[startOffset+1:])
+}
+
+// scanLineComment consumes a '#' or '//' comment through end of line.
+func (s *Scanner) scanLineComment() string {
+	start := s.offset
+	for s.offset < len(s.src) && s.src[s.offset] != '\n' {
+		s.offset++
+	}
+	return string(s.src[start:s.offset])
+}
+
+// scanBlockComment consumes a '/* ... */' comment.
+func (s *Scanner) scanBlockComment(start Pos) string {
+	startOffset := s.offset
+	s.offset += 2
+	for s.offset < len(s.src) {
+		if s.src[s.offset] == '\n' {
+			s.file.AddLine(s.offset + 1)
+		}
+		if s.src[s.offset] == '*' && s.peek(1) == '/' {
+			s.offset += 2
+			return string(s.src[startOffset:s.offset])
+		}
+		s.offset++
+	}
+	s.error(start, "block comment not terminated")
+	return string(s.src[startOffset:])
+}
+
+// scanWord consumes a maximal run of non-delimiter bytes. This single rule
+// is what lets address-match-list entries -- "!10.0.0.0/8", "fe80::/10",
+// key names such as "rndc-key", "any", "none" -- come back as one IDENT each, rather
+// than being split on the punctuation BIND allows inside them.
+func (s *Scanner) scanWord() string {
+	start := s.offset
+	for s.offset < len(s.src) && !isDelimiter(s.src[s.offset]) {
+		s.offset++
+	}
+	return string(s.src[start:s.offset])
+}
+
+func classify(lit string) Token {
+	if lit == "" {
+		return ILLEGAL
+	}
+	if strings.EqualFold(lit, "include") {
+		return INCLUDE
+	}
+	for i := 0; i < len(lit); i++ {
+		if lit[i] < '0' || lit[i] > '9' {
+			return IDENT
+		}
+	}
+	return NUMBER
+}
+
+// TokenInfo is one entry of a Tokenize result.
+type TokenInfo struct {
+	Pos     Pos
+	Token   Token
+	Literal string
+}
+
+// Tokenize lexes src in a single pass and returns every token, including a
+// trailing EOF. It is the entry point for callers that just want the token
+// stream without managing a Scanner themselves.
+func Tokenize(src []byte) []TokenInfo {
+	var s Scanner
+	s.Init(src, nil, nil)
+	var out []TokenInfo
+	for {
+		pos, tok, lit := s.Scan()
+		out = append(out, TokenInfo{Pos: pos, Token: tok, Literal: lit})
+		if tok == EOF {
+			return out
+		}
+	}
+}
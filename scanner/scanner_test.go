@@ -0,0 +1,53 @@
+package scanner
+
+import "testing"
+
+func TestTokenizeBasic(t *testing.T) {
+	src := []byte(`options {
+  recursion no;
+  allow-query { !10.0.0.0/8; any; };
+};
+include "/etc/named.rfc1912.zones"; # trailing
+`)
+	toks := Tokenize(src)
+	if len(toks) == 0 || toks[len(toks)-1].Token != EOF {
+		t.Fatalf("expected a trailing EOF token, got %v", toks)
+	}
+
+	var got []Token
+	for _, tk := range toks {
+		if tk.Token != EOF {
+			got = append(got, tk.Token)
+		}
+	}
+	want := []Token{
+		IDENT, LBRACE,
+		IDENT, IDENT, SEMI,
+		IDENT, LBRACE, IDENT, SEMI, IDENT, SEMI, RBRACE, SEMI,
+		RBRACE, SEMI,
+		INCLUDE, STRING, SEMI,
+		COMMENT,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("token count mismatch\ngot:  %v\nwant: %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("token %d: got %v, want %v\nall got: %v", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestScannerAddressMatchList(t *testing.T) {
+	var s Scanner
+	s.Init([]byte(`!10.0.0.0/8 fe80::/10 any;`), nil, nil)
+
+	_, tok, lit := s.Scan()
+	if tok != IDENT || lit != "!10.0.0.0/8" {
+		t.Fatalf("got %v %q, want IDENT !10.0.0.0/8", tok, lit)
+	}
+	_, tok, lit = s.Scan()
+	if tok != IDENT || lit != "fe80::/10" {
+		t.Fatalf("got %v %q, want IDENT fe80::/10", tok, lit)
+	}
+}
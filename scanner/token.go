@@ -0,0 +1,114 @@
+// Package scanner implements a lexer for BIND's named.conf grammar. It is
+// deliberately standalone: it has no dependency on the namedconf tree
+// builder, so external tools (linters, syntax highlighters, formatters) can
+// reuse the token stream without pulling in the CST/AST layers.
+package scanner
+
+import "fmt"
+
+// Token identifies the lexical class of a token returned by Scan.
+type Token int
+
+const (
+	ILLEGAL Token = iota
+	EOF
+
+	COMMENT // '#...', '//...' or '/* ... */'
+	IDENT   // a bare word: keywords, names, and address-match-list entries such as !10.0.0.0/8 or ::1
+	STRING  // a double-quoted string literal, with quotes stripped
+	NUMBER  // a word consisting solely of ASCII digits
+	INCLUDE // the "include" keyword
+
+	LBRACE // {
+	RBRACE // }
+	SEMI   // ;
+)
+
+var tokenNames = map[Token]string{
+	ILLEGAL: "ILLEGAL",
+	EOF:     "EOF",
+	COMMENT: "COMMENT",
+	IDENT:   "IDENT",
+	STRING:  "STRING",
+	NUMBER:  "NUMBER",
+	INCLUDE: "INCLUDE",
+	LBRACE:  "LBRACE",
+	RBRACE:  "RBRACE",
+	SEMI:    "SEMI",
+}
+
+// String returns the token's name, e.g. "IDENT".
+func (t Token) String() string {
+	if s, ok := tokenNames[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Token(%d)", int(t))
+}
+
+// Pos is a byte offset into the source passed to Scanner.Init. Unlike
+// namedconf.Pos it is not FileSet-relative -- it is meaningful only within
+// the single source the Scanner was initialized with. Callers that need
+// line/column positions can resolve a Pos via a File.
+type Pos int
+
+// Position is a resolved line/column location, analogous to go/token.Position.
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+func (p Position) String() string {
+	name := p.Filename
+	if name == "" {
+		name = "-"
+	}
+	return fmt.Sprintf("%s:%d:%d", name, p.Line, p.Column)
+}
+
+// File tracks line-start offsets for a single source, letting a Pos be
+// resolved to a line/column pair. It is the scanner package's self-contained
+// analogue of go/token.File; it is unrelated to namedconf.File.
+type File struct {
+	Name  string
+	lines []int // byte offsets of line starts; lines[0] is always 0
+}
+
+// NewFile creates a File ready to have lines recorded into it during a scan.
+func NewFile(name string) *File {
+	return &File{Name: name, lines: []int{0}}
+}
+
+// AddLine records the offset of a line start. Offsets must be added in
+// increasing order; out-of-order or duplicate offsets are ignored.
+func (f *File) AddLine(offset int) {
+	if f == nil {
+		return
+	}
+	if n := len(f.lines); n == 0 || f.lines[n-1] < offset {
+		f.lines = append(f.lines, offset)
+	}
+}
+
+// Position resolves pos against the recorded line starts.
+func (f *File) Position(pos Pos) Position {
+	if f == nil {
+		return Position{}
+	}
+	offset := int(pos)
+	lo, hi := 0, len(f.lines)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if f.lines[mid] > offset {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	lineStart := 0
+	if lo > 0 {
+		lineStart = f.lines[lo-1]
+	}
+	return Position{Filename: f.Name, Offset: offset, Line: lo, Column: offset - lineStart + 1}
+}
@@ -0,0 +1,47 @@
+package namedconf
+
+import "bytes"
+
+// CommentKind identifies which of BIND's three comment styles a Comment uses.
+type CommentKind int
+
+const (
+	HashComment       CommentKind = iota // '# ...'
+	SlashSlashComment                    // '// ...'
+	BlockComment                         // '/* ... */'
+)
+
+// Comment is a single comment, preserved verbatim including its delimiters.
+// Comments are produced as their own Node (rather than folded into Raw
+// trivia) so they can be addressed individually, e.g. by CommentMap.
+type Comment struct {
+	Text       string
+	Kind       CommentKind
+	start, end int
+	pos, endP  Pos
+}
+
+func (*Comment) isNode()                     {}
+func (c *Comment) writeTo(buf *bytes.Buffer) { buf.WriteString(c.Text) }
+func (c *Comment) Start() int                { return c.start }
+func (c *Comment) End() int                  { return c.end }
+func (c *Comment) Pos() Pos                  { return c.pos }
+func (c *Comment) EndPos() Pos               { return c.endP }
+
+// Whitespace is inter-statement spacing and newlines with no comment
+// content, split out from what used to be a single opaque Raw blob so
+// comments can be told apart from the blank lines around them.
+type Whitespace struct {
+	Text       string
+	start, end int
+	pos, endP  Pos
+}
+
+func (*Whitespace) isNode() {}
+func (w *Whitespace) writeTo(buf *bytes.Buffer) {
+	buf.WriteString(defaultPrinter.renderRaw(w.Text))
+}
+func (w *Whitespace) Start() int  { return w.start }
+func (w *Whitespace) End() int    { return w.end }
+func (w *Whitespace) Pos() Pos    { return w.pos }
+func (w *Whitespace) EndPos() Pos { return w.endP }
@@ -15,6 +15,10 @@ type Node interface {
 	// Start and End are byte offsets into the original source for unchanged nodes.
 	Start() int
 	End() int
+	// Pos and EndPos are FileSet-relative positions covering the same range
+	// as Start/End, resolvable to line/column via File.Position.
+	Pos() Pos
+	EndPos() Pos
 }
 
 // File is a parsed named.conf file.
@@ -22,6 +26,30 @@ type File struct {
 	Nodes []Node
 	src   []byte
 	path  string
+
+	fset   *FileSet
+	errors []*ParseError
+}
+
+// Position resolves pos (as returned by a Node's Pos/EndPos) to a filename,
+// line, column and offset. It returns the zero Position if f has no
+// FileSet.
+func (f *File) Position(pos Pos) Position {
+	return f.fset.Position(pos)
+}
+
+// FileSet returns the FileSet this file was parsed with. Parse uses a fresh,
+// unnamed FileSet; a File not produced by a parse function has none (nil).
+func (f *File) FileSet() *FileSet {
+	return f.fset
+}
+
+// Errors returns the parse errors encountered while building the typed
+// statement tree. Regions that produced an error are preserved as Raw nodes
+// rather than dropped, so a non-empty Errors() does not mean bytes were
+// lost -- it means some region was tolerated rather than cleanly parsed.
+func (f *File) Errors() []*ParseError {
+	return f.errors
 }
 
 // Bytes returns the serialized bytes (lossless if unchanged).
@@ -91,40 +119,56 @@ func (f *File) Find(pred func(*Stmt) bool) []*Stmt {
 	return out
 }
 
-// ParseFile parses a named.conf file from disk.
+// ParseFile parses a named.conf file from disk, registering it with a fresh
+// FileSet under its absolute path so File.Position renders useful filenames.
 func ParseFile(path string) (*File, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	f, err := Parse(b)
+	abs, err := filepath.Abs(path)
 	if err != nil {
-		return nil, err
+		abs = path
 	}
-	f.path, _ = filepath.Abs(path)
-	return f, nil
+	return ParseWithFileSet(NewFileSet(), abs, b)
 }
 
-// Parse parses a named.conf from bytes.
+// Parse parses a named.conf from bytes using a throwaway, unnamed FileSet.
+// Use ParseWithFileSet directly when positions need to resolve to a
+// filename, or when several files must share one FileSet (e.g. for
+// cross-file diagnostics across includes).
 func Parse(src []byte) (*File, error) {
-	p := &parser{src: src}
+	return ParseWithFileSet(NewFileSet(), "", src)
+}
+
+// ParseWithFileSet parses src, registering it with fset under filename so
+// that Pos values returned by the resulting File's nodes resolve to
+// (filename, line, column) via fset.Position or File.Position.
+func ParseWithFileSet(fset *FileSet, filename string, src []byte) (*File, error) {
+	pf := fset.addFile(filename, len(src))
+	p := &parser{src: src, file: pf}
 	nodes, err := p.parseRange(0, len(src))
 	if err != nil {
 		return nil, err
 	}
-	return &File{Nodes: nodes, src: src}, nil
+	return &File{Nodes: nodes, src: src, path: filename, fset: fset, errors: p.errors}, nil
 }
 
 // Raw preserves uninterpreted text (whitespace + comments between statements).
 type Raw struct {
 	Text       string
 	start, end int
+	pos, endP  Pos
 }
 
-func (*Raw) isNode()                     {}
-func (r *Raw) writeTo(buf *bytes.Buffer) { buf.WriteString(r.Text) }
-func (r *Raw) Start() int                { return r.start }
-func (r *Raw) End() int                  { return r.end }
+func (*Raw) isNode() {}
+func (r *Raw) writeTo(buf *bytes.Buffer) {
+	buf.WriteString(defaultPrinter.renderRaw(r.Text))
+}
+func (r *Raw) Start() int  { return r.start }
+func (r *Raw) End() int    { return r.end }
+func (r *Raw) Pos() Pos    { return r.pos }
+func (r *Raw) EndPos() Pos { return r.endP }
 
 // Stmt represents a single named.conf statement ending with ';' (possibly after a block).
 // It preserves the exact original text (RawText) for perfect round-tripping when unmodified.
@@ -132,6 +176,7 @@ type Stmt struct {
 	// Original bytes for lossless re-emit when Modified==false
 	RawText    string
 	start, end int
+	pos, endP  Pos
 
 	// Structured view (best-effort, tolerant)
 	Keyword        string // first identifier-like token (lowercased)
@@ -144,61 +189,23 @@ type Stmt struct {
 
 	// If any field is edited, set Modified=true to regenerate; otherwise RawText is emitted.
 	Modified bool
+
+	// docComments and lineComment cache the associations CommentMap exposes,
+	// computed once at parse time by linkComments. See CommentMap.Doc/Line.
+	docComments []*Comment
+	lineComment *Comment
 }
 
-func (*Stmt) isNode()      {}
-func (s *Stmt) Start() int { return s.start }
-func (s *Stmt) End() int   { return s.end }
+func (*Stmt) isNode()       {}
+func (s *Stmt) Start() int  { return s.start }
+func (s *Stmt) End() int    { return s.end }
+func (s *Stmt) Pos() Pos    { return s.pos }
+func (s *Stmt) EndPos() Pos { return s.endP }
 
-// Write regenerates if Modified; otherwise emits original RawText.
+// writeTo regenerates s if Modified, otherwise emits its original RawText.
+// The actual rendering logic lives in Printer; this just applies the
+// package's default style so File.Bytes/File.Save/File.WriteTo keep their
+// historical output without needing to know Printer exists.
 func (s *Stmt) writeTo(buf *bytes.Buffer) {
-	if !s.Modified && s.RawText != "" {
-		buf.WriteString(s.RawText)
-		return
-	}
-	// Regenerate with minimal, stable formatting.
-	if !s.HasBlock {
-		if s.HeadRaw == "" {
-			buf.WriteString(s.RawText)
-			return
-		}
-		buf.WriteString(trimRightSpace(s.HeadRaw))
-		buf.WriteByte(';')
-		return
-	}
-	// Block stmt
-	// Print head, open brace, body (indented), close brace, semicolon.
-	head := trimRightSpace(s.HeadRaw)
-	if head == "" {
-		head = s.Keyword
-	}
-	buf.WriteString(head)
-	buf.WriteString(" {")
-	// Indent body by two spaces if not empty.
-	if len(s.Body) > 0 {
-		buf.WriteByte('\n')
-		for _, n := range s.Body {
-			// indent each body node
-			var inner bytes.Buffer
-			n.writeTo(&inner)
-			// Ensure each line is indented
-			lines := bytes.Split(inner.Bytes(), []byte("\n"))
-			for i, ln := range lines {
-				if i < len(lines)-1 {
-					buf.WriteString("  ")
-					buf.Write(ln)
-					buf.WriteByte('\n')
-				} else if len(ln) > 0 { // last line w/o newline
-					buf.WriteString("  ")
-					buf.Write(ln)
-				}
-			}
-		}
-		// Ensure trailing newline before closing brace
-		if last := buf.Bytes(); len(last) == 0 || last[len(last)-1] != '\n' {
-			buf.WriteByte('\n')
-		}
-	}
-	buf.WriteString("}")
-	buf.WriteString(";")
+	defaultPrinter.writeStmt(buf, s)
 }